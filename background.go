@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+
+	"github.com/nsf/termbox-go"
+)
+
+// BackgroundLayer — один слой параллакс-фона. Слои с большим ScrollSpeed
+// скроллятся заметнее и выглядят ближе к игроку
+type BackgroundLayer struct {
+	Sprite      Sprite
+	ScrollSpeed float64           // доля от levelConfig.ObstacleSpeed
+	Color       termbox.Attribute
+	Height      int // высота нижнего края слоя над землёй, в строках
+	Offset      float64
+}
+
+// defaultBackgrounds возвращает слои параллакс-фона по умолчанию: дальние
+// горы у горизонта почти не двигаются, ближние облака выше и скроллятся заметнее
+func defaultBackgrounds() []BackgroundLayer {
+	return []BackgroundLayer{
+		{
+			Sprite: Sprite{
+				"  /\\      /\\      /\\  ",
+				" /  \\    /  \\    /  \\ ",
+			},
+			ScrollSpeed: 0.25,
+			Color:       termbox.ColorBlue,
+			Height:      2,
+		},
+		{
+			Sprite: Sprite{
+				" .--.       .--.   ",
+				"(____)     (____)  ",
+			},
+			ScrollSpeed: 0.5,
+			Color:       termbox.ColorWhite,
+			Height:      8,
+		},
+	}
+}
+
+// advanceBackgrounds продвигает офсеты слоёв параллакса пропорционально
+// их ScrollSpeed и текущей скорости препятствий, заворачивая офсет по
+// ширине тайла, чтобы слой бесшовно повторялся по горизонтали
+func (g *Game) advanceBackgrounds(obstacleSpeed int) {
+	for i := range g.Backgrounds {
+		layer := &g.Backgrounds[i]
+		layer.Offset += layer.ScrollSpeed * float64(obstacleSpeed)
+
+		width := float64(len(layer.Sprite[0]))
+		if width > 0 {
+			layer.Offset = math.Mod(layer.Offset, width)
+		}
+	}
+}
+
+// drawParallaxLayer рисует один слой фона, замощая его тайлами по всей
+// ширине экрана с учётом текущего офсета прокрутки
+func drawParallaxLayer(s Screen, groundY int, layer BackgroundLayer, bg termbox.Attribute) {
+	width, _ := s.Size()
+	tileWidth := len(layer.Sprite[0])
+	if tileWidth == 0 {
+		return
+	}
+
+	y := groundY - layer.Height - len(layer.Sprite) + 1
+	for x := -int(layer.Offset); x < width; x += tileWidth {
+		DrawSprite(s, x, y, layer.Sprite, layer.Color, bg)
+	}
+}
+
+// dayNightStop описывает цветовую палитру в одной точке суточного цикла
+type dayNightStop struct {
+	Ground termbox.Attribute
+	Sky    termbox.Attribute
+}
+
+// dayNightCycle — последовательность остановок, через которые проходит
+// смена дня и ночи: день, закат, ночь, рассвет
+var dayNightCycle = []dayNightStop{
+	{Ground: termbox.ColorGreen, Sky: termbox.ColorDefault},
+	{Ground: termbox.ColorYellow, Sky: termbox.ColorRed},
+	{Ground: termbox.ColorBlue, Sky: termbox.ColorBlack},
+	{Ground: termbox.ColorCyan, Sky: termbox.ColorMagenta},
+}
+
+// currentDayNightStop возвращает палитру, действующую в данный момент
+// суточного цикла, управляемого FrameCount и Config.DayNightPeriod
+func (g *Game) currentDayNightStop() dayNightStop {
+	stopLength := g.Config.DayNightPeriod / len(dayNightCycle)
+	if stopLength == 0 {
+		return dayNightCycle[0]
+	}
+
+	index := (g.FrameCount / stopLength) % len(dayNightCycle)
+	return dayNightCycle[index]
+}
@@ -0,0 +1,60 @@
+package main
+
+import "time"
+
+// Clock абстрагирует источник тиков игрового цикла. Обычная игра тикает
+// с реальным интервалом, а при воспроизведении повтора тики выдаются
+// по запросу, без ожидания реального времени
+type Clock interface {
+	Tick() <-chan time.Time
+}
+
+// RealClock тикает через time.Ticker с заданным интервалом кадра
+type RealClock struct {
+	ticker *time.Ticker
+}
+
+// NewRealClock создаёт часы, тикающие с интервалом d
+func NewRealClock(d time.Duration) *RealClock {
+	return &RealClock{ticker: time.NewTicker(d)}
+}
+
+func (c *RealClock) Tick() <-chan time.Time {
+	return c.ticker.C
+}
+
+// Stop останавливает внутренний тикер
+func (c *RealClock) Stop() {
+	c.ticker.Stop()
+}
+
+// ReplayClock выдаёт тик только по явному вызову Advance, что позволяет
+// воспроизводить повтор кадр за кадром без реальных задержек
+type ReplayClock struct {
+	ch  chan time.Time
+	ack chan struct{}
+}
+
+// NewReplayClock создаёт часы для управляемого воспроизведения повтора
+func NewReplayClock() *ReplayClock {
+	return &ReplayClock{ch: make(chan time.Time), ack: make(chan struct{})}
+}
+
+func (c *ReplayClock) Tick() <-chan time.Time {
+	return c.ch
+}
+
+// Ack подтверждает, что RunGame завершил Update/Render для кадра,
+// запрошенного последним вызовом Advance
+func (c *ReplayClock) Ack() {
+	c.ack <- struct{}{}
+}
+
+// Advance посылает один тик и ждёт подтверждения, что RunGame завершил
+// обработку этого кадра — это гарантирует, что к моменту возврата из
+// Advance FrameCount уже обновлён и следующую порцию ввода можно подавать
+// без гонки за данными
+func (c *ReplayClock) Advance() {
+	c.ch <- time.Now()
+	<-c.ack
+}
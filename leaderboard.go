@@ -0,0 +1,50 @@
+package main
+
+import (
+	"sort"
+	"sync"
+)
+
+// LeaderboardEntry хранит итоговый результат одного завершённого забега
+type LeaderboardEntry struct {
+	Name  string
+	Score int
+}
+
+// Leaderboard агрегирует лучшие результаты по всем SSH-сессиям.
+// Доступ к нему защищён мьютексом, так как каждая сессия обновляет
+// его из собственной горутины
+type Leaderboard struct {
+	mu      sync.Mutex
+	entries []LeaderboardEntry
+	top     int
+}
+
+// NewLeaderboard создаёт таблицу лидеров, хранящую top лучших результатов
+func NewLeaderboard(top int) *Leaderboard {
+	return &Leaderboard{top: top}
+}
+
+// Submit добавляет результат игрока и обрезает таблицу до top лучших
+func (l *Leaderboard) Submit(name string, score int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, LeaderboardEntry{Name: name, Score: score})
+	sort.Slice(l.entries, func(i, j int) bool {
+		return l.entries[i].Score > l.entries[j].Score
+	})
+	if len(l.entries) > l.top {
+		l.entries = l.entries[:l.top]
+	}
+}
+
+// Top возвращает копию текущей таблицы лидеров
+func (l *Leaderboard) Top() []LeaderboardEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make([]LeaderboardEntry, len(l.entries))
+	copy(result, l.entries)
+	return result
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"math"
 	"math/rand"
 	"time"
 
@@ -12,8 +14,7 @@ import (
 type GameConfig struct {
 	PlayerX           int           // Позиция игрока по X
 	GroundY           int           // Позиция земли
-	JumpHeight        int           // Высота прыжка
-	JumpDuration      int           // Продолжительность прыжка
+	JumpHeight        int           // Ориентировочная высота прыжка (для расстановки препятствий)
 	InitialLives      int           // Начальное количество жизней
 	BaseObstacleSpeed int           // Базовая скорость препятствий
 	FrameRate         time.Duration // Частота обновления кадров
@@ -21,6 +22,13 @@ type GameConfig struct {
 	ScreenWidth       int           // Ширина экрана
 	MaxSpeed          int           // Максимальная скорость препятствий
 	MinSpawnRate      int           // Минимальная частота появления препятствий
+
+	Gravity      float64 // Ускорение, прибавляемое к VelocityY каждый кадр
+	JumpImpulse  float64 // Начальная скорость вверх, задаваемая прыжком
+	CoyoteFrames int     // Окно кадров после схода с земли, в течение которого прыжок ещё засчитывается
+	MaxJumps     int     // Количество прыжков подряд без приземления (2 — один двойной прыжок)
+
+	DayNightPeriod int // длительность полного цикла дня и ночи в кадрах
 }
 
 // DefaultGameConfig возвращает конфигурацию игры по умолчанию
@@ -29,7 +37,6 @@ func DefaultGameConfig() GameConfig {
 		PlayerX:           10,
 		GroundY:           15,
 		JumpHeight:        10,
-		JumpDuration:      18,
 		InitialLives:      5,
 		BaseObstacleSpeed: 1,
 		FrameRate:         32 * time.Millisecond,
@@ -37,15 +44,20 @@ func DefaultGameConfig() GameConfig {
 		ScreenWidth:       80,
 		MaxSpeed:          5,
 		MinSpawnRate:      10,
+
+		Gravity:      0.25,
+		JumpImpulse:  2.25,
+		CoyoteFrames: 3,
+		MaxJumps:     2,
+
+		DayNightPeriod: 2400,
 	}
 }
 
-// Типы препятствий
+// Параметры прыжка, не зависящие от конкретного уровня сложности
 const (
-	ObstacleRock = iota
-	ObstacleBox
-	ObstacleTree
-	ObstacleTypesCount
+	jumpCutDamping     = 0.5 // во сколько раз гасится скорость вверх при отпускании пробела
+	keyHoldGraceFrames = 2   // сколько кадров клавиша считается всё ещё зажатой между событиями
 )
 
 // Sprite представляет графическое изображение объекта
@@ -55,6 +67,7 @@ type Sprite []string
 type Obstacle struct {
 	X    int
 	Type int
+	Hit  bool // уже отняло жизнь у игрока; не должно делать этого повторно
 }
 
 // Game содержит игровое состояние
@@ -62,34 +75,98 @@ type Game struct {
 	Config     GameConfig
 	PlayerY    int
 	IsJumping  bool
-	JumpTime   int
 	Lives      int
 	Obstacles  []Obstacle
 	FrameCount int
 	Score      int
 	Sprites    struct {
-		Player    Sprite
-		Obstacles []Sprite
+		Player     Sprite
+		PlayerDuck Sprite
 	}
+
+	// Физика прыжка: скорость по вертикали, счётчик кадров с момента
+	// последнего касания земли (для "coyote time") и оставшиеся прыжки
+	// в воздухе (двойной прыжок)
+	VelocityY            float64
+	FramesSinceGrounded  int
+	JumpsRemaining       int
+	SpaceHeld            bool
+	SpaceReleaseDeadline int
+
+	// Приседание позволяет пропускать летающие препятствия, временно
+	// уменьшая габарит игрока, используемый в CheckCollision
+	IsDucking           bool
+	DuckReleaseDeadline int
+
+	// Бонусы и их эффекты: щит поглощает одно столкновение, замедление
+	// снижает скорость скролла, неуязвимость не даёт терять жизни от того
+	// же столкновения несколько кадров подряд, а комбо растёт за каждое
+	// успешно пройденное препятствие и сбрасывается при получении урона
+	Powerups         []Powerup
+	HasShield        bool
+	SlowMoFrames     int
+	InvincibleFrames int
+	Combo            int
+
+	// Backgrounds хранит слои параллакс-фона, рисуемые от дальнего к
+	// ближнему перед землёй, препятствиями и игроком
+	Backgrounds []BackgroundLayer
+
+	// Seed и Rng обеспечивают детерминированность забега: вся случайность
+	// игры идёт через Rng, что позволяет в точности повторить игру по
+	// одному и тому же Seed и журналу ввода
+	Seed int64
+	Rng  *rand.Rand
+
+	// Recording включает запись ввода в InputLog; при воспроизведении
+	// повтора выключается, чтобы не переписать исходный журнал
+	Recording bool
+	InputLog  []RecordedInput
+}
+
+// RecordedInput фиксирует одно событие ввода и номер кадра, на котором
+// оно произошло, — этого достаточно, чтобы точно воспроизвести забег
+type RecordedInput struct {
+	Frame int
+	Key   termbox.Key
+	Ch    rune
+}
+
+// Event восстанавливает termbox.Event из записанного ввода
+func (r RecordedInput) Event() termbox.Event {
+	return termbox.Event{Type: termbox.EventKey, Key: r.Key, Ch: r.Ch}
 }
 
 // LevelConfig содержит настройки сложности текущего уровня
 type LevelConfig struct {
 	ObstacleSpeed int
 	SpawnRate     int
+	Level         int // увеличивается каждые 10 очков, открывает новые виды препятствий
 }
 
-// NewGame создаёт новую игру с указанной конфигурацией
-func NewGame(config GameConfig) *Game {
+// NewGame создаёт новую игру с указанной конфигурацией. Необязательный
+// seed фиксирует генератор случайных чисел игры, что используется системой
+// повторов; если seed не передан, используется текущее время
+func NewGame(config GameConfig, seed ...int64) *Game {
+	s := time.Now().UnixNano()
+	if len(seed) > 0 {
+		s = seed[0]
+	}
+
 	game := &Game{
-		Config:     config,
-		PlayerY:    config.GroundY,
-		IsJumping:  false,
-		JumpTime:   0,
-		Lives:      config.InitialLives,
-		Obstacles:  []Obstacle{},
-		FrameCount: 0,
-		Score:      0,
+		Config:         config,
+		PlayerY:        config.GroundY,
+		IsJumping:      false,
+		Lives:          config.InitialLives,
+		Obstacles:      []Obstacle{},
+		Powerups:       []Powerup{},
+		FrameCount:     0,
+		Score:          0,
+		Seed:           s,
+		Rng:            rand.New(rand.NewSource(s)),
+		Recording:      true,
+		JumpsRemaining: config.MaxJumps,
+		Backgrounds:    defaultBackgrounds(),
 	}
 
 	// Инициализация спрайтов
@@ -98,26 +175,8 @@ func NewGame(config GameConfig) *Game {
 		"/|\\",
 		"/ \\",
 	}
-
-	game.Sprites.Obstacles = []Sprite{
-		// Камень
-		{
-			" /\\ ",
-			"/__\\",
-		},
-		// Ящик
-		{
-			"+--+",
-			"|  |",
-			"+--+",
-		},
-		// Дерево
-		{
-			" /\\ ",
-			"/  \\",
-			" || ",
-			" || ",
-		},
+	game.Sprites.PlayerDuck = Sprite{
+		"_O_",
 	}
 
 	return game
@@ -147,55 +206,91 @@ func (g *Game) GetLevelConfig() LevelConfig {
 	return LevelConfig{
 		ObstacleSpeed: speed,
 		SpawnRate:     spawnRate,
+		Level:         levelIncrease,
 	}
 }
 
 // DrawText выводит текст на заданной позиции
-func DrawText(x, y int, msg string, fg, bg termbox.Attribute) {
+func DrawText(s Screen, x, y int, msg string, fg, bg termbox.Attribute) {
 	for i, ch := range msg {
-		termbox.SetCell(x+i, y, ch, fg, bg)
+		s.SetCell(x+i, y, ch, fg, bg)
 	}
 }
 
 // DrawSprite выводит многострочный спрайт на экран
-func DrawSprite(x, y int, sprite Sprite, fg, bg termbox.Attribute) {
+func DrawSprite(s Screen, x, y int, sprite Sprite, fg, bg termbox.Attribute) {
 	for dy, line := range sprite {
 		for dx, ch := range line {
-			termbox.SetCell(x+dx, y+dy, rune(ch), fg, bg)
+			s.SetCell(x+dx, y+dy, rune(ch), fg, bg)
 		}
 	}
 }
 
-// Render отрисовывает текущее состояние игры
-func (g *Game) Render() {
-	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+// Render отрисовывает текущее состояние игры на переданном экране,
+// что позволяет использовать один и тот же игровой цикл как локально,
+// так и для удалённых SSH-сессий
+func (g *Game) Render(s Screen) {
+	stop := g.currentDayNightStop()
+	s.Clear(termbox.ColorDefault, stop.Sky)
+
+	// Параллакс-фон рисуется первым, так как он дальше всего от игрока;
+	// обычные слои идут поверх него в порядке от дальнего к ближнему
+	for _, layer := range g.Backgrounds {
+		drawParallaxLayer(s, g.Config.GroundY, layer, stop.Sky)
+	}
 
 	// Отрисовка земли
 	for x := 0; x < g.Config.ScreenWidth; x++ {
-		termbox.SetCell(x, g.Config.GroundY+1, '_', termbox.ColorGreen, termbox.ColorDefault)
+		s.SetCell(x, g.Config.GroundY+1, '_', stop.Ground, stop.Sky)
 	}
 
 	// Отрисовка игрока
-	playerY := g.PlayerY - len(g.Sprites.Player) + 1
-	DrawSprite(g.Config.PlayerX, playerY, g.Sprites.Player, termbox.ColorYellow, termbox.ColorDefault)
+	playerSprite := g.Sprites.Player
+	if g.IsDucking {
+		playerSprite = g.Sprites.PlayerDuck
+	}
+	playerY := g.PlayerY - len(playerSprite) + 1
+	DrawSprite(s, g.Config.PlayerX, playerY, playerSprite, termbox.ColorYellow, stop.Sky)
 
 	// Отрисовка препятствий
 	for _, o := range g.Obstacles {
-		model := g.Sprites.Obstacles[o.Type]
-		y := g.Config.GroundY - len(model) + 1
-		DrawSprite(o.X, y, model, termbox.ColorRed, termbox.ColorDefault)
+		kind := obstacleCatalog[o.Type]
+		y := g.Config.GroundY - len(kind.Sprite) + 1 - kind.HeightOffset
+		DrawSprite(s, o.X, y, kind.Sprite, termbox.ColorRed, stop.Sky)
+	}
+
+	// Отрисовка бонусов
+	for _, p := range g.Powerups {
+		visual := powerupVisuals[p.Kind]
+		y := g.Config.GroundY - len(visual.Sprite) + 1
+		DrawSprite(s, p.X, y, visual.Sprite, visual.Color, stop.Sky)
 	}
 
 	// Отрисовка информации
 	levelConfig := g.GetLevelConfig()
-	info := fmt.Sprintf("Lives: %d | Score: %d | Speed: %d", g.Lives, g.Score, levelConfig.ObstacleSpeed)
-	DrawText(0, 0, info, termbox.ColorWhite, termbox.ColorDefault)
+	info := fmt.Sprintf("Lives: %d | Score: %d | Speed: %d | Combo: x%d", g.Lives, g.Score, levelConfig.ObstacleSpeed, comboMultiplier(g.Combo))
+	DrawText(s, 0, 0, info, termbox.ColorWhite, stop.Sky)
+
+	// Отрисовка активных бонусов
+	status := ""
+	if g.HasShield {
+		status += "[Shield] "
+	}
+	if g.SlowMoFrames > 0 {
+		status += "[SlowMo] "
+	}
+	if !g.IsDucking && g.approachingDuckObstacle() {
+		status += "[Duck!] "
+	}
+	if status != "" {
+		DrawText(s, 0, 1, status, termbox.ColorCyan, stop.Sky)
+	}
 
 	// Отрисовка инструкций
-	instructions := "Space: Jump | ESC/Q: Quit"
-	DrawText(g.Config.ScreenWidth-len(instructions), 0, instructions, termbox.ColorWhite, termbox.ColorDefault)
+	instructions := "Space: Jump | Down: Duck | ESC/Q: Quit"
+	DrawText(s, g.Config.ScreenWidth-len(instructions), 0, instructions, termbox.ColorWhite, stop.Sky)
 
-	termbox.Flush()
+	s.Flush()
 }
 
 // Update обновляет состояние игры
@@ -203,59 +298,134 @@ func (g *Game) Update() {
 	// Получаем текущую конфигурацию уровня
 	levelConfig := g.GetLevelConfig()
 
-	// Обработка прыжка
+	// Обработка прыжка: гравитационная симуляция вместо параболы по кадрам
 	if g.IsJumping {
-		g.JumpTime++
-		if g.JumpTime < g.Config.JumpDuration/2 {
-			// Подъем
-			jumpProgress := float64(g.JumpTime) / float64(g.Config.JumpDuration/2)
-			g.PlayerY = g.Config.GroundY - int(float64(g.Config.JumpHeight)*jumpProgress)
-		} else if g.JumpTime < g.Config.JumpDuration {
-			// Спуск
-			fallProgress := float64(g.JumpTime-g.Config.JumpDuration/2) / float64(g.Config.JumpDuration/2)
-			g.PlayerY = g.Config.GroundY - g.Config.JumpHeight + int(float64(g.Config.JumpHeight)*fallProgress)
-		} else {
-			// Приземление
+		// Срезание прыжка (jump-cut): если пробел отпущен до апекса, гасим
+		// скорость вверх, давая более короткий и отзывчивый прыжок
+		if g.FrameCount > g.SpaceReleaseDeadline {
+			g.SpaceHeld = false
+		}
+		if g.VelocityY < 0 && !g.SpaceHeld {
+			g.VelocityY *= jumpCutDamping
+		}
+
+		g.VelocityY += g.Config.Gravity
+		g.PlayerY += int(math.Round(g.VelocityY))
+
+		if g.PlayerY >= g.Config.GroundY {
 			g.PlayerY = g.Config.GroundY
+			g.VelocityY = 0
 			g.IsJumping = false
-			g.JumpTime = 0
 		}
 	}
 
+	// Отслеживание времени с последнего касания земли для coyote time
+	// и пополнение прыжков в воздухе (двойной прыжок) при приземлении
+	if g.PlayerY == g.Config.GroundY {
+		g.FramesSinceGrounded = 0
+		g.JumpsRemaining = g.Config.MaxJumps
+	} else {
+		g.FramesSinceGrounded++
+	}
+
+	// Приседание считается активным, пока приходят повторные события
+	// стрелки вниз; по истечении grace-периода оно снимается
+	if g.FrameCount > g.DuckReleaseDeadline {
+		g.IsDucking = false
+	}
+
+	// Неуязвимость после получения урона и замедление от PowerSlowMo
+	if g.InvincibleFrames > 0 {
+		g.InvincibleFrames--
+	}
+	obstacleSpeed := levelConfig.ObstacleSpeed
+	if g.SlowMoFrames > 0 {
+		g.SlowMoFrames--
+		obstacleSpeed = slowMoObstacleSpeed(obstacleSpeed)
+	}
+
+	g.advanceBackgrounds(obstacleSpeed)
+
 	// Обновление препятствий
+	playerSprite := g.Sprites.Player
+	if g.IsDucking {
+		playerSprite = g.Sprites.PlayerDuck
+	}
+	playerWidth := len(playerSprite[0])
+	playerHeight := len(playerSprite)
+
 	newObstacles := []Obstacle{}
 	for _, o := range g.Obstacles {
-		o.X -= levelConfig.ObstacleSpeed
+		o.X -= obstacleSpeed
 
 		// Проверка столкновений
-		obstacleWidth := len(g.Sprites.Obstacles[o.Type][0])
-		obstacleHeight := len(g.Sprites.Obstacles[o.Type])
-		playerWidth := len(g.Sprites.Player[0])
-		playerHeight := len(g.Sprites.Player)
-
-		// Более точное определение столкновений
-		if CheckCollision(
+		kind := obstacleCatalog[o.Type]
+		obstacleWidth := len(kind.Sprite[0])
+		obstacleHeight := len(kind.Sprite)
+		obstacleY := g.Config.GroundY - obstacleHeight + 1 - kind.HeightOffset
+
+		// Более точное определение столкновений. o.Hit гарантирует, что одно
+		// и то же препятствие отнимет жизнь не больше одного раза, даже если
+		// оно продолжает перекрываться с игроком несколько кадров подряд
+		if !o.Hit && CheckCollision(
 			g.Config.PlayerX, g.PlayerY-playerHeight+1, playerWidth, playerHeight,
-			o.X, g.Config.GroundY-obstacleHeight+1, obstacleWidth, obstacleHeight,
+			o.X, obstacleY, obstacleWidth, obstacleHeight,
 		) {
-			g.Lives--
+			o.Hit = true
+			if g.InvincibleFrames == 0 {
+				if g.HasShield {
+					g.HasShield = false
+				} else {
+					g.Lives--
+				}
+				g.Combo = 0
+				g.InvincibleFrames = invincibilityGraceFrames
+			}
 		}
 
 		if o.X > -obstacleWidth {
 			newObstacles = append(newObstacles, o)
-		} else {
-			// Увеличение счета при успешном пропуске препятствия
-			g.Score++
+		} else if !o.Hit {
+			// Успешный пропуск препятствия поднимает комбо-множитель очков
+			g.Combo++
+			g.Score += comboMultiplier(g.Combo)
 		}
 	}
 	g.Obstacles = newObstacles
 
-	// Создание новых препятствий
-	if g.FrameCount%levelConfig.SpawnRate == 0 && rand.Intn(3) > 0 {
-		obstacleType := rand.Intn(ObstacleTypesCount)
+	// Создание новых препятствий: взвешенный случайный выбор вида среди
+	// доступных на текущем уровне сложности
+	if g.FrameCount%levelConfig.SpawnRate == 0 && g.Rng.Intn(3) > 0 {
 		g.Obstacles = append(g.Obstacles, Obstacle{
 			X:    g.Config.ScreenWidth,
-			Type: obstacleType,
+			Type: g.pickObstacleKind(levelConfig.Level),
+		})
+	}
+
+	// Обновление бонусов: двигаются в том же скролле, что и препятствия
+	newPowerups := []Powerup{}
+	for _, p := range g.Powerups {
+		p.X -= obstacleSpeed
+		sprite := powerupVisuals[p.Kind].Sprite
+
+		if CheckCollision(
+			g.Config.PlayerX, g.PlayerY-playerHeight+1, playerWidth, playerHeight,
+			p.X, g.Config.GroundY-len(sprite)+1, len(sprite[0]), len(sprite),
+		) {
+			g.applyPowerup(p.Kind)
+			continue
+		}
+
+		if p.X > -len(sprite[0]) {
+			newPowerups = append(newPowerups, p)
+		}
+	}
+	g.Powerups = newPowerups
+
+	if g.FrameCount%powerupSpawnRate == 0 && g.Rng.Intn(4) == 0 {
+		g.Powerups = append(g.Powerups, Powerup{
+			X:    g.Config.ScreenWidth,
+			Kind: g.Rng.Intn(powerupKindsCount),
 		})
 	}
 
@@ -267,23 +437,45 @@ func CheckCollision(x1, y1, w1, h1, x2, y2, w2, h2 int) bool {
 	return x1 < x2+w2 && x1+w1 > x2 && y1 < y2+h2 && y1+h1 > y2
 }
 
-// HandleInput обрабатывает пользовательский ввод
+// HandleInput обрабатывает пользовательский ввод, попутно записывая его
+// в InputLog вместе с текущим FrameCount для последующего воспроизведения
 func (g *Game) HandleInput(ev termbox.Event) bool {
 	if ev.Type == termbox.EventKey {
+		if g.Recording {
+			g.InputLog = append(g.InputLog, RecordedInput{Frame: g.FrameCount, Key: ev.Key, Ch: ev.Ch})
+		}
+
 		switch {
 		case ev.Key == termbox.KeyEsc || ev.Ch == 'q':
 			return false
-		case ev.Key == termbox.KeySpace && !g.IsJumping:
-			g.IsJumping = true
+		case ev.Key == termbox.KeySpace:
+			g.SpaceHeld = true
+			g.SpaceReleaseDeadline = g.FrameCount + keyHoldGraceFrames
+
+			grounded := g.PlayerY == g.Config.GroundY || g.FramesSinceGrounded <= g.Config.CoyoteFrames
+			if grounded || g.JumpsRemaining > 0 {
+				g.VelocityY = -g.Config.JumpImpulse
+				g.IsJumping = true
+				if grounded {
+					g.JumpsRemaining = g.Config.MaxJumps - 1
+				} else {
+					g.JumpsRemaining--
+				}
+			}
+		case ev.Key == termbox.KeyArrowDown:
+			g.IsDucking = true
+			g.DuckReleaseDeadline = g.FrameCount + keyHoldGraceFrames
 		}
 	}
 	return true
 }
 
-// DrawGameOver отображает экран окончания игры
-func DrawGameOver(score int) {
-	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
-	width, height := termbox.Size()
+// DrawGameOver отображает экран окончания игры и ждёт нажатия клавиши,
+// полученной через переданный канал событий, что позволяет использовать
+// его как с termbox.PollEvent, так и с событиями, разобранными из SSH-канала
+func DrawGameOver(s Screen, events <-chan termbox.Event, score int) {
+	s.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	width, height := s.Size()
 
 	// Графика "GAME OVER"
 	gameOverArt := []string{
@@ -296,55 +488,85 @@ func DrawGameOver(score int) {
 	// Рисуем ASCII-арт
 	artY := height/2 - len(gameOverArt) - 2
 	for i, line := range gameOverArt {
-		DrawText(width/2-len(line)/2, artY+i, line, termbox.ColorRed, termbox.ColorDefault)
+		DrawText(s, width/2-len(line)/2, artY+i, line, termbox.ColorRed, termbox.ColorDefault)
 	}
 
 	// Сообщение о завершении игры
 	finalScore := fmt.Sprintf("Final Score: %d", score)
 	exitMsg := "Press any key to exit"
 
-	DrawText(width/2-len(finalScore)/2, height/2+3, finalScore, termbox.ColorYellow, termbox.ColorDefault)
-	DrawText(width/2-len(exitMsg)/2, height/2+5, exitMsg, termbox.ColorWhite, termbox.ColorDefault)
+	DrawText(s, width/2-len(finalScore)/2, height/2+3, finalScore, termbox.ColorYellow, termbox.ColorDefault)
+	DrawText(s, width/2-len(exitMsg)/2, height/2+5, exitMsg, termbox.ColorWhite, termbox.ColorDefault)
 
-	termbox.Flush()
+	s.Flush()
 
 	// Ожидание нажатия клавиши
-	termbox.PollEvent()
+	<-events
 }
 
-// RunGame запускает игровой цикл
-func RunGame(game *Game) {
-	// Создание игрового цикла
-	gameLoop := time.NewTicker(game.Config.FrameRate)
-	defer gameLoop.Stop()
-
-	// Канал для событий пользовательского ввода
-	eventQueue := make(chan termbox.Event)
-	go func() {
-		for {
-			eventQueue <- termbox.PollEvent()
-		}
-	}()
-
+// RunGame запускает игровой цикл, рисуя в переданный экран, читая ввод
+// из переданного канала событий и беря тики из переданных часов. Это
+// позволяет запускать одну и ту же игру локально (экран termbox, события
+// termbox.PollEvent, RealClock), в рамках SSH-сессии (экран и события,
+// привязанные к конкретному подключению) и при воспроизведении повтора
+// (ReplayClock, тикающие синхронно с записанным вводом)
+func RunGame(game *Game, screen Screen, events <-chan termbox.Event, clock Clock) {
 	// Главный цикл игры
 	running := true
 	for running && game.Lives > 0 {
 		select {
-		case <-gameLoop.C:
+		case <-clock.Tick():
 			game.Update()
-			game.Render()
-		case ev := <-eventQueue:
+			game.Render(screen)
+			if acker, ok := clock.(interface{ Ack() }); ok {
+				acker.Ack()
+			}
+		case ev := <-events:
 			running = game.HandleInput(ev)
 		}
 	}
 
+	// Сохранение повтора для завершённого забега
+	if game.Recording {
+		if err := game.SaveReplay(defaultReplayPath(game)); err != nil {
+			fmt.Printf("не удалось сохранить повтор: %v\n", err)
+		}
+	}
+
 	// Отображение экрана завершения игры
-	DrawGameOver(game.Score)
+	DrawGameOver(screen, events, game.Score)
+}
+
+// pollTermboxEvents публикует события локального termbox-терминала
+// в канал, пригодный для RunGame
+func pollTermboxEvents() <-chan termbox.Event {
+	eventQueue := make(chan termbox.Event)
+	go func() {
+		for {
+			eventQueue <- termbox.PollEvent()
+		}
+	}()
+	return eventQueue
 }
 
 func main() {
-	// Инициализация генератора случайных чисел
-	rand.Seed(time.Now().UnixNano())
+	serveAddr := flag.String("serve", "", "запустить SSH-сервер для многопользовательской игры (например :2222)")
+	replayPath := flag.String("replay", "", "воспроизвести повтор из .replay файла вместо обычной игры")
+	flag.Parse()
+
+	if *serveAddr != "" {
+		if err := ServeSSH(*serveAddr); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *replayPath != "" {
+		if err := ReplayGame(*replayPath); err != nil {
+			panic(err)
+		}
+		return
+	}
 
 	// Инициализация termbox
 	err := termbox.Init()
@@ -365,5 +587,7 @@ func main() {
 
 	// Создание и запуск игры
 	game := NewGame(config)
-	RunGame(game)
+	clock := NewRealClock(config.FrameRate)
+	defer clock.Stop()
+	RunGame(game, TermboxScreen{}, pollTermboxEvents(), clock)
 }
@@ -0,0 +1,119 @@
+package main
+
+// ObstacleKind описывает один вид препятствия: как он выглядит, на какой
+// высоте над землёй рисуется, с каким весом выбирается спавном и требует
+// ли приседания вместо прыжка
+type ObstacleKind struct {
+	Name         string
+	Sprite       Sprite
+	HeightOffset int  // высота нижнего края спрайта над землёй (0 — стоит на земле)
+	SpawnWeight  int  // вес при взвешенном случайном выборе
+	RequiresDuck bool // true для летающих препятствий, которые нужно пропускать пригнувшись
+	MinLevel     int  // минимальный Level (см. LevelConfig), с которого вид может появиться
+}
+
+// obstacleCatalog — реестр всех известных видов препятствий; индекс в
+// этом срезе используется как Obstacle.Type
+var obstacleCatalog []ObstacleKind
+
+// RegisterObstacle добавляет вид препятствия в каталог и возвращает его
+// индекс, чтобы новые виды можно было вводить, не трогая логику Update
+func RegisterObstacle(kind ObstacleKind) int {
+	obstacleCatalog = append(obstacleCatalog, kind)
+	return len(obstacleCatalog) - 1
+}
+
+// Встроенные виды препятствий, доступные в базовой комплектации игры
+var (
+	ObstacleRock = RegisterObstacle(ObstacleKind{
+		Name: "Камень",
+		Sprite: Sprite{
+			" /\\ ",
+			"/__\\",
+		},
+		SpawnWeight: 3,
+	})
+
+	ObstacleBox = RegisterObstacle(ObstacleKind{
+		Name: "Ящик",
+		Sprite: Sprite{
+			"+--+",
+			"|  |",
+			"+--+",
+		},
+		SpawnWeight: 3,
+	})
+
+	ObstacleTree = RegisterObstacle(ObstacleKind{
+		Name: "Дерево",
+		Sprite: Sprite{
+			" /\\ ",
+			"/  \\",
+			" || ",
+			" || ",
+		},
+		SpawnWeight: 2,
+	})
+
+	// ObstacleBird — летающее препятствие, под которым нужно пройти
+	// пригнувшись; летит на высоте чуть выше стандартного прыжка
+	// (GroundY - JumpHeight - 1), так что обычный прыжок в него не спасает
+	ObstacleBird = RegisterObstacle(ObstacleKind{
+		Name: "Птица",
+		Sprite: Sprite{
+			"^-^",
+		},
+		HeightOffset: birdHeightOffset,
+		SpawnWeight:  2,
+		RequiresDuck: true,
+		MinLevel:     2,
+	})
+)
+
+// birdHeightOffset поднимает птицу на JumpHeight+1 над землёй, опираясь
+// на высоту прыжка из конфигурации по умолчанию
+var birdHeightOffset = DefaultGameConfig().JumpHeight + 1
+
+// duckWarningDistance — расстояние до препятствия, на котором HUD
+// предупреждает игрока, что впереди препятствие, требующее приседания
+const duckWarningDistance = 10
+
+// approachingDuckObstacle сообщает, есть ли впереди игрока в пределах
+// duckWarningDistance препятствие вида с RequiresDuck, под которое нужно
+// пройти пригнувшись, а не перепрыгнуть
+func (g *Game) approachingDuckObstacle() bool {
+	for _, o := range g.Obstacles {
+		kind := obstacleCatalog[o.Type]
+		if kind.RequiresDuck && o.X >= g.Config.PlayerX && o.X-g.Config.PlayerX <= duckWarningDistance {
+			return true
+		}
+	}
+	return false
+}
+
+// pickObstacleKind взвешенно выбирает индекс вида препятствия среди тех,
+// что уже открыты на текущем уровне сложности
+func (g *Game) pickObstacleKind(level int) int {
+	total := 0
+	for _, kind := range obstacleCatalog {
+		if kind.MinLevel <= level {
+			total += kind.SpawnWeight
+		}
+	}
+	if total == 0 {
+		return ObstacleRock
+	}
+
+	roll := g.Rng.Intn(total)
+	for i, kind := range obstacleCatalog {
+		if kind.MinLevel > level {
+			continue
+		}
+		if roll < kind.SpawnWeight {
+			return i
+		}
+		roll -= kind.SpawnWeight
+	}
+
+	return ObstacleRock
+}
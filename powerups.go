@@ -0,0 +1,65 @@
+package main
+
+import "github.com/nsf/termbox-go"
+
+// Виды бонусов. Бонусы появляются в том же скролле, что и препятствия,
+// и подбираются тем же столкновением с игроком
+const (
+	PowerShield = iota
+	PowerSlowMo
+	PowerExtraLife
+	powerupKindsCount
+)
+
+const (
+	invincibilityGraceFrames = 15 // кадров неуязвимости после получения урона
+	slowMoDurationFrames     = 90 // сколько кадров действует замедление
+	powerupSpawnRate         = 150
+)
+
+// Powerup представляет подбираемый бонус, движущийся по экрану вместе
+// с препятствиями
+type Powerup struct {
+	X    int
+	Kind int
+}
+
+// powerupVisual описывает, как бонус рисуется на экране
+type powerupVisual struct {
+	Sprite Sprite
+	Color  termbox.Attribute
+}
+
+var powerupVisuals = [powerupKindsCount]powerupVisual{
+	PowerShield:    {Sprite: Sprite{"[o]"}, Color: termbox.ColorCyan},
+	PowerSlowMo:    {Sprite: Sprite{"(~)"}, Color: termbox.ColorBlue},
+	PowerExtraLife: {Sprite: Sprite{"(+)"}, Color: termbox.ColorMagenta},
+}
+
+// applyPowerup активирует эффект подобранного бонуса
+func (g *Game) applyPowerup(kind int) {
+	switch kind {
+	case PowerShield:
+		g.HasShield = true
+	case PowerSlowMo:
+		g.SlowMoFrames = slowMoDurationFrames
+	case PowerExtraLife:
+		g.Lives++
+	}
+}
+
+// slowMoObstacleSpeed вдвое снижает скорость препятствий на время
+// действия PowerSlowMo, не давая ей упасть до нуля
+func slowMoObstacleSpeed(speed int) int {
+	half := speed / 2
+	if half < 1 {
+		return 1
+	}
+	return half
+}
+
+// comboMultiplier переводит число подряд пройденных препятствий в
+// множитель очков: каждые 5 препятствий подряд добавляют +1 к множителю
+func comboMultiplier(combo int) int {
+	return 1 + combo/5
+}
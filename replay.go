@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// ReplayData — это всё, что нужно, чтобы в точности повторить забег:
+// сид генератора случайных чисел, конфигурация и журнал ввода по кадрам
+type ReplayData struct {
+	Seed       int64
+	Config     GameConfig
+	Inputs     []RecordedInput
+	FinalScore int
+}
+
+// SaveReplay сохраняет повтор текущего забега в файл в формате JSON
+func (g *Game) SaveReplay(path string) error {
+	data := ReplayData{
+		Seed:       g.Seed,
+		Config:     g.Config,
+		Inputs:     g.InputLog,
+		FinalScore: g.Score,
+	}
+
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, bytes, 0o644)
+}
+
+// LoadReplay читает ранее сохранённый повтор из файла
+func LoadReplay(path string) (*ReplayData, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ReplayData
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// defaultReplayPath формирует имя файла повтора по времени завершения забега
+func defaultReplayPath(g *Game) string {
+	return fmt.Sprintf("game-%s.replay", time.Now().Format("20060102-150405"))
+}
+
+// ReplayGame воспроизводит ранее записанный забег: восстанавливает игру
+// с тем же сидом и конфигурацией, а затем вместо termbox.PollEvent подаёт
+// в HandleInput события из журнала в момент, когда FrameCount совпадает
+// с записанным кадром
+func ReplayGame(path string) error {
+	data, err := LoadReplay(path)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать повтор: %w", err)
+	}
+
+	game := NewGame(data.Config, data.Seed)
+	game.Recording = false
+
+	if err := termbox.Init(); err != nil {
+		return err
+	}
+	defer termbox.Close()
+	termbox.SetInputMode(termbox.InputEsc)
+
+	events := make(chan termbox.Event)
+	clock := NewReplayClock()
+	done := make(chan struct{})
+
+	go feedReplayInputs(data.Inputs, game, events, clock, done)
+	go forwardRealEvents(pollTermboxEvents(), events, done)
+
+	RunGame(game, TermboxScreen{}, events, clock)
+
+	if game.Score != data.FinalScore {
+		fmt.Printf("предупреждение: итоговый счёт воспроизведения (%d) отличается от записанного (%d)\n", game.Score, data.FinalScore)
+	}
+
+	return nil
+}
+
+// feedReplayInputs подаёт записанные события ввода на нужных кадрах и
+// продвигает часы повтора, воспроизводя забег кадр за кадром. По
+// завершении забега закрывает done, сигнализируя, что записанный ввод
+// исчерпан и реальные нажатия клавиш больше не перепутать с ним
+func feedReplayInputs(inputs []RecordedInput, game *Game, events chan<- termbox.Event, clock *ReplayClock, done chan<- struct{}) {
+	i := 0
+	for game.Lives > 0 {
+		for i < len(inputs) && inputs[i].Frame == game.FrameCount {
+			events <- inputs[i].Event()
+			i++
+		}
+		clock.Advance()
+	}
+	close(done)
+}
+
+// forwardRealEvents пересылает реальный пользовательский ввод в общий
+// канал событий повтора, но только после того, как забег завершится и
+// done закроется. До этого момента реальные нажатия клавиш (например,
+// случайный пробел) не должны попадать в HandleInput — иначе они
+// вмешаются в детерминированное воспроизведение. После закрытия done
+// канал нужен лишь затем, чтобы разбудить DrawGameOver на "Press any key"
+func forwardRealEvents(real <-chan termbox.Event, events chan<- termbox.Event, done <-chan struct{}) {
+	<-done
+	for ev := range real {
+		events <- ev
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import "github.com/nsf/termbox-go"
+
+// Screen абстрагирует отрисовку игры, позволяя одной и той же игровой
+// логике рисовать либо в локальный терминал через termbox, либо в
+// PTY удалённой SSH-сессии.
+type Screen interface {
+	Clear(fg, bg termbox.Attribute)
+	SetCell(x, y int, ch rune, fg, bg termbox.Attribute)
+	Size() (int, int)
+	Flush() error
+}
+
+// TermboxScreen реализует Screen поверх локального termbox-терминала.
+type TermboxScreen struct{}
+
+func (TermboxScreen) Clear(fg, bg termbox.Attribute) {
+	termbox.Clear(fg, bg)
+}
+
+func (TermboxScreen) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	termbox.SetCell(x, y, ch, fg, bg)
+}
+
+func (TermboxScreen) Size() (int, int) {
+	return termbox.Size()
+}
+
+func (TermboxScreen) Flush() error {
+	return termbox.Flush()
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/nsf/termbox-go"
+	"golang.org/x/crypto/ssh"
+)
+
+// sharedLeaderboard хранит лучшие результаты по всем подключениям к
+// SSH-серверу, пережившим перезапуск отдельных сессий (но не сервера)
+var sharedLeaderboard = NewLeaderboard(10)
+
+// lobby отслеживает игроков, подключённых к серверу в данный момент,
+// чтобы показывать их в лобби перед стартом игры
+type lobby struct {
+	mu      sync.Mutex
+	players map[string]bool
+}
+
+func newLobby() *lobby {
+	return &lobby{players: map[string]bool{}}
+}
+
+func (l *lobby) join(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.players[name] = true
+}
+
+func (l *lobby) leave(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.players, name)
+}
+
+func (l *lobby) names() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := make([]string, 0, len(l.players))
+	for name := range l.players {
+		names = append(names, name)
+	}
+	return names
+}
+
+var sharedLobby = newLobby()
+
+// ptyRequestPayload описывает полезную нагрузку SSH-запроса "pty-req"
+type ptyRequestPayload struct {
+	Term     string
+	Width    uint32
+	Height   uint32
+	PixWidth uint32
+	PixHeigh uint32
+	Modes    string
+}
+
+// windowChangePayload описывает полезную нагрузку запроса "window-change"
+type windowChangePayload struct {
+	Width    uint32
+	Height   uint32
+	PixWidth uint32
+	PixHeigh uint32
+}
+
+// ServeSSH поднимает SSH-сервер на заданном адресе; каждое подключение
+// получает собственный экземпляр *Game, выполняющийся в отдельной
+// горутине и рисующий в свой виртуальный терминал
+func ServeSSH(addr string) error {
+	signer, err := newHostKeySigner()
+	if err != nil {
+		return fmt.Errorf("не удалось сгенерировать ключ хоста: %w", err)
+	}
+
+	config := &ssh.ServerConfig{
+		NoClientAuth: true,
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("не удалось запустить слушатель: %w", err)
+	}
+	log.Printf("SSH-сервер запущен на %s", addr)
+
+	for {
+		nConn, err := listener.Accept()
+		if err != nil {
+			log.Printf("ошибка приёма подключения: %v", err)
+			continue
+		}
+		go handleConn(nConn, config)
+	}
+}
+
+func newHostKeySigner() (ssh.Signer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.NewSignerFromKey(key)
+}
+
+// handleConn обрабатывает одно SSH-подключение: согласовывает сессию,
+// ждёт запрос shell/pty и запускает отдельную игру для этого игрока
+func handleConn(nConn net.Conn, config *ssh.ServerConfig) {
+	defer nConn.Close()
+
+	sconn, chans, reqs, err := ssh.NewServerConn(nConn, config)
+	if err != nil {
+		log.Printf("рукопожатие SSH не удалось: %v", err)
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	playerName := fmt.Sprintf("%s@%s", sconn.User(), nConn.RemoteAddr())
+	sharedLobby.join(playerName)
+	defer sharedLobby.leave(playerName)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "поддерживаются только session-каналы")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			log.Printf("не удалось принять канал: %v", err)
+			continue
+		}
+
+		go handleSession(channel, requests, playerName)
+	}
+}
+
+// handleSession обслуживает один session-канал: ждёт pty и shell, затем
+// показывает лобби и запускает игру, пока канал открыт
+func handleSession(channel ssh.Channel, requests <-chan *ssh.Request, playerName string) {
+	defer channel.Close()
+
+	width, height := 80, 24
+	screen := NewSSHScreen(channel, width, height)
+	events := make(chan termbox.Event)
+	shellStarted := make(chan bool, 1)
+
+	go func() {
+		for req := range requests {
+			switch req.Type {
+			case "pty-req":
+				var p ptyRequestPayload
+				if ssh.Unmarshal(req.Payload, &p) == nil && p.Width > 0 && p.Height > 0 {
+					width, height = int(p.Width), int(p.Height)
+					screen.Resize(width, height)
+				}
+				req.Reply(true, nil)
+			case "window-change":
+				var p windowChangePayload
+				if ssh.Unmarshal(req.Payload, &p) == nil && p.Width > 0 && p.Height > 0 {
+					screen.Resize(int(p.Width), int(p.Height))
+				}
+			case "shell":
+				req.Reply(true, nil)
+				shellStarted <- true
+			default:
+				req.Reply(false, nil)
+			}
+		}
+	}()
+
+	go readInputEvents(channel, events)
+
+	select {
+	case <-shellStarted:
+	case <-time.After(10 * time.Second):
+		return
+	}
+
+	showLobby(screen, events, playerName)
+
+	config := DefaultGameConfig()
+	config.ScreenWidth = width
+	game := NewGame(config)
+	// Сервер принимает анонимные подключения без аутентификации, поэтому
+	// не пишет повторы на диск хоста: иначе игрок мог бы переподключаться
+	// в цикле и забить диск, а сессии, завершившиеся в одну секунду,
+	// затирали бы файлы друг друга (defaultReplayPath не различает сессии)
+	game.Recording = false
+
+	clock := NewRealClock(config.FrameRate)
+	defer clock.Stop()
+	RunGame(game, screen, events, clock)
+	sharedLeaderboard.Submit(playerName, game.Score)
+	showStandings(screen, events)
+}
+
+// readInputEvents читает байты, присылаемые SSH-клиентом, и превращает
+// их в термбокс-совместимые события для HandleInput. Стрелки приходят как
+// ESC-последовательности ("\x1b[A".."\x1b[D"), поэтому после одиночного
+// ESC мы ненадолго ждём продолжения, прежде чем считать это нажатием Esc
+func readInputEvents(channel ssh.Channel, events chan<- termbox.Event) {
+	raw := make(chan byte)
+	go func() {
+		defer close(raw)
+		buf := make([]byte, 1)
+		for {
+			n, err := channel.Read(buf)
+			if err != nil || n == 0 {
+				return
+			}
+			raw <- buf[0]
+		}
+	}()
+
+	for b := range raw {
+		switch b {
+		case ' ':
+			events <- termbox.Event{Type: termbox.EventKey, Key: termbox.KeySpace}
+		case 'q', 'Q':
+			events <- termbox.Event{Type: termbox.EventKey, Ch: 'q'}
+		case 0x1b:
+			if !forwardEscapeSequence(raw, events) {
+				return
+			}
+		default:
+			events <- termbox.Event{Type: termbox.EventKey, Ch: rune(b)}
+		}
+	}
+}
+
+// forwardEscapeSequence разбирает то, что идёт сразу за ESC: CSI-стрелку
+// ("[A".."[D") или одиночное нажатие Esc, если продолжения не последовало.
+// Возвращает false, если канал ввода закрылся
+func forwardEscapeSequence(raw <-chan byte, events chan<- termbox.Event) bool {
+	select {
+	case next, ok := <-raw:
+		if !ok {
+			return false
+		}
+		if next != '[' {
+			events <- termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEsc}
+			return true
+		}
+
+		dir, ok := <-raw
+		if !ok {
+			return false
+		}
+		switch dir {
+		case 'A':
+			events <- termbox.Event{Type: termbox.EventKey, Key: termbox.KeyArrowUp}
+		case 'B':
+			events <- termbox.Event{Type: termbox.EventKey, Key: termbox.KeyArrowDown}
+		case 'C':
+			events <- termbox.Event{Type: termbox.EventKey, Key: termbox.KeyArrowRight}
+		case 'D':
+			events <- termbox.Event{Type: termbox.EventKey, Key: termbox.KeyArrowLeft}
+		}
+		return true
+	case <-time.After(50 * time.Millisecond):
+		events <- termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEsc}
+		return true
+	}
+}
+
+// showLobby показывает список подключённых игроков и таблицу лидеров
+// перед стартом игры конкретного игрока
+func showLobby(s Screen, events <-chan termbox.Event, playerName string) {
+	s.Clear(termbox.ColorDefault, termbox.ColorDefault)
+
+	DrawText(s, 2, 1, "=== go_game_runner: lobby ===", termbox.ColorYellow, termbox.ColorDefault)
+	DrawText(s, 2, 3, fmt.Sprintf("Вы подключились как %s", playerName), termbox.ColorWhite, termbox.ColorDefault)
+
+	DrawText(s, 2, 5, "Сейчас играют:", termbox.ColorWhite, termbox.ColorDefault)
+	for i, name := range sharedLobby.names() {
+		DrawText(s, 4, 6+i, name, termbox.ColorCyan, termbox.ColorDefault)
+	}
+
+	standingsY := 7 + len(sharedLobby.names())
+	DrawText(s, 2, standingsY, "Таблица лидеров:", termbox.ColorWhite, termbox.ColorDefault)
+	for i, entry := range sharedLeaderboard.Top() {
+		DrawText(s, 4, standingsY+1+i, fmt.Sprintf("%d. %s - %d", i+1, entry.Name, entry.Score), termbox.ColorGreen, termbox.ColorDefault)
+	}
+
+	DrawText(s, 2, standingsY+12, "Нажмите любую клавишу, чтобы начать", termbox.ColorWhite, termbox.ColorDefault)
+	s.Flush()
+
+	<-events
+}
+
+// showStandings показывает обновлённую таблицу лидеров после завершения забега
+func showStandings(s Screen, events <-chan termbox.Event) {
+	s.Clear(termbox.ColorDefault, termbox.ColorDefault)
+
+	DrawText(s, 2, 1, "=== Таблица лидеров ===", termbox.ColorYellow, termbox.ColorDefault)
+	for i, entry := range sharedLeaderboard.Top() {
+		DrawText(s, 4, 3+i, fmt.Sprintf("%d. %s - %d", i+1, entry.Name, entry.Score), termbox.ColorGreen, termbox.ColorDefault)
+	}
+	DrawText(s, 2, 15, "Нажмите любую клавишу, чтобы отключиться", termbox.ColorWhite, termbox.ColorDefault)
+	s.Flush()
+
+	<-events
+}
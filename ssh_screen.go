@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/nsf/termbox-go"
+)
+
+// sshCell хранит содержимое одной ячейки виртуального экрана SSH-сессии
+type sshCell struct {
+	ch     rune
+	fg, bg termbox.Attribute
+}
+
+// SSHScreen реализует Screen поверх обычного io.Writer (SSH-канала),
+// перерисовывая экран ANSI-последовательностями. В отличие от termbox,
+// который владеет единственным реальным терминалом, SSHScreen существует
+// в одном экземпляре на каждое подключение. mu защищает cells/width/height,
+// потому что Resize вызывается из горутины, обрабатывающей SSH-запросы
+// "window-change", одновременно с Render, работающим в горутине игры
+type SSHScreen struct {
+	mu            sync.Mutex
+	w             io.Writer
+	width, height int
+	cells         [][]sshCell
+}
+
+// NewSSHScreen создаёт виртуальный экран заданного размера, привязанный
+// к каналу записи конкретной SSH-сессии
+func NewSSHScreen(w io.Writer, width, height int) *SSHScreen {
+	s := &SSHScreen{w: w, width: width, height: height}
+	s.cells = make([][]sshCell, height)
+	for y := range s.cells {
+		s.cells[y] = make([]sshCell, width)
+	}
+	return s
+}
+
+// Resize меняет размер виртуального экрана, например в ответ на
+// SSH-запрос "window-change"
+func (s *SSHScreen) Resize(width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.width, s.height = width, height
+	s.cells = make([][]sshCell, height)
+	for y := range s.cells {
+		s.cells[y] = make([]sshCell, width)
+	}
+}
+
+func (s *SSHScreen) Clear(fg, bg termbox.Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for y := range s.cells {
+		for x := range s.cells[y] {
+			s.cells[y][x] = sshCell{ch: ' ', fg: fg, bg: bg}
+		}
+	}
+}
+
+func (s *SSHScreen) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if x < 0 || y < 0 || y >= len(s.cells) || x >= len(s.cells[y]) {
+		return
+	}
+	s.cells[y][x] = sshCell{ch: ch, fg: fg, bg: bg}
+}
+
+func (s *SSHScreen) Size() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.width, s.height
+}
+
+// ansiColor переводит атрибут termbox в код цвета ANSI для SGR-последовательности
+func ansiColor(attr termbox.Attribute, background bool) int {
+	base := 30
+	if background {
+		base = 40
+	}
+	if attr == termbox.ColorDefault {
+		return base + 9
+	}
+	// termbox нумерует базовые цвета с ColorBlack = 1
+	return base + int(attr) - 1
+}
+
+// Flush перерисовывает весь экран, переводя курсор в начало и выводя
+// накопленные ячейки построчно с ANSI-кодами цвета
+func (s *SSHScreen) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := make([]byte, 0, len(s.cells)*s.width*4)
+	buf = append(buf, "\x1b[H"...)
+
+	noAttr := ^termbox.Attribute(0)
+	curFg, curBg := noAttr, noAttr
+	for y, row := range s.cells {
+		for _, c := range row {
+			if c.fg != curFg || c.bg != curBg {
+				buf = append(buf, fmt.Sprintf("\x1b[0m\x1b[%dm\x1b[%dm", ansiColor(c.fg, false), ansiColor(c.bg, true))...)
+				curFg, curBg = c.fg, c.bg
+			}
+			buf = append(buf, string(c.ch)...)
+		}
+		buf = append(buf, "\x1b[0m"...)
+		if y != len(s.cells)-1 {
+			buf = append(buf, "\r\n"...)
+		}
+		curFg, curBg = noAttr, noAttr
+	}
+
+	_, err := s.w.Write(buf)
+	return err
+}